@@ -8,8 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/wurt83ow/alice-skill/internal/logger"
+	"github.com/wurt83ow/alice-skill/internal/metrics"
 	"github.com/wurt83ow/alice-skill/internal/models"
+	"github.com/wurt83ow/alice-skill/internal/queue"
 	"github.com/wurt83ow/alice-skill/internal/store"
 	"go.uber.org/zap"
 )
@@ -17,33 +20,35 @@ import (
 // app инкапсулирует в себя все зависимости и логику приложения
 type app struct {
 	store store.Store
+	queue queue.Client
 }
 
 // newApp принимает на вход внешние зависимости приложения и возвращает новый объект app
-func newApp(s store.Store) *app {
-	return &app{store: s}
+func newApp(s store.Store, q queue.Client) *app {
+	return &app{store: s, queue: q}
 }
 
 func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	metrics.WebhookRequestsTotal.Inc()
 
 	if r.Method != http.MethodPost {
-		logger.Log.Debug("got request with bad method", zap.String("method", r.Method))
+		logger.FromContext(ctx).Debug("got request with bad method", zap.String("method", r.Method))
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 
-	logger.Log.Debug("decoding request")
+	logger.FromContext(ctx).Debug("decoding request")
 	var req models.Request
 	dec := json.NewDecoder(r.Body)
 	if err := dec.Decode(&req); err != nil {
-		logger.Log.Debug("cannot decode request JSON body", zap.Error(err))
+		logger.FromContext(ctx).Debug("cannot decode request JSON body", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if req.Request.Type != models.TypeSimpleUtterance {
-		logger.Log.Debug("unsupported request type", zap.String("type", req.Request.Type))
+		logger.FromContext(ctx).Debug("unsupported request type", zap.String("type", req.Request.Type))
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		return
 	}
@@ -53,25 +58,38 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 
 	switch true {
 	case strings.HasPrefix(req.Request.Command, "Отправь"):
+		defer metrics.ObserveCommand("send")()
+
 		// гипотетическая функция parseSendCommand вычленит из запроса логин адресата и текст сообщения
 		username, message := parseSendCommand(req.Request.Command)
 
 		// найдём внутренний идентификатор адресата по его логину
 		recepientID, err := a.store.FindRecepient(ctx, username)
 		if err != nil {
-			logger.Log.Debug("cannot find recepient by username", zap.String("username", username), zap.Error(err))
+			logger.FromContext(ctx).Debug("cannot find recepient by username", zap.String("username", username), zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		// сохраняем новое сообщение в СУБД, после успешного сохранения оно станет доступно для прослушивания получателем
-		err = a.store.SaveMessage(ctx, recepientID, store.Message{
-			Sender:  req.Session.User.UserID,
-			Time:    time.Now(),
-			Payload: message,
+		// вместо синхронной записи в СУБД ставим задачу в очередь: обработчик
+		// вебхука отвечает быстро даже тогда, когда БД под нагрузкой, а
+		// sent_at получатель увидит только после того, как воркер её сохранит
+		payload, err := json.Marshal(queue.SendMessagePayload{
+			RequestID:      logger.RequestID(ctx),
+			Sender:         req.Session.User.UserID,
+			RecepientID:    recepientID,
+			Text:           message,
+			SentAt:         time.Now(),
+			IdempotencyKey: uuid.NewString(),
 		})
 		if err != nil {
-			logger.Log.Debug("cannot save message", zap.String("recepient", recepientID), zap.Error(err))
+			logger.FromContext(ctx).Debug("cannot marshal send message task", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if err := a.queue.Enqueue(ctx, queue.Task{Type: queue.TypeSendMessage, Payload: payload}); err != nil {
+			logger.FromContext(ctx).Debug("cannot enqueue send message task", zap.String("recepient", recepientID), zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -81,13 +99,15 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 
 		// пользователь попросил прочитать сообщение
 	case strings.HasPrefix(req.Request.Command, "Прочитай"):
+		defer metrics.ObserveCommand("read")()
+
 		// гипотетическая функция parseSendCommand вычленит из запроса порядковый номер сообщения в списке доступных
 		messageIndex := parseReadCommand(req.Request.Command)
 
 		// получим список непрослушанных сообщений пользователя
 		messages, err := a.store.ListMessages(ctx, req.Session.User.UserID)
 		if err != nil {
-			logger.Log.Debug("cannot load messages for user", zap.Error(err))
+			logger.FromContext(ctx).Debug("cannot load messages for user", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -101,7 +121,7 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 			messageID := messages[messageIndex].ID
 			message, err := a.store.GetMessage(ctx, messageID)
 			if err != nil {
-				logger.Log.Debug("cannot load message", zap.Int64("id", messageID), zap.Error(err))
+				logger.FromContext(ctx).Debug("cannot load message", zap.Int64("id", messageID), zap.Error(err))
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
@@ -112,6 +132,8 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 
 	// пользователь хочет зарегистрироваться
 	case strings.HasPrefix(req.Request.Command, "Зарегистрируй"):
+		defer metrics.ObserveCommand("register")()
+
 		// гипотетическая функция parseRegisterCommand вычленит из запроса
 		// желаемое имя нового пользователя
 		username := parseRegisterCommand(req.Request.Command)
@@ -120,7 +142,7 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 		err := a.store.RegisterUser(ctx, req.Session.User.UserID, username)
 		// наличие неспецифичной ошибки
 		if err != nil && !errors.Is(err, store.ErrConflict) {
-			logger.Log.Debug("cannot register user", zap.Error(err))
+			logger.FromContext(ctx).Debug("cannot register user", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -134,9 +156,11 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 
 	// если не поняли команду, просто скажем пользовутелю сколько у него новых сообщений
 	default:
+		defer metrics.ObserveCommand("default")()
+
 		messages, err := a.store.ListMessages(ctx, req.Session.User.UserID)
 		if err != nil {
-			logger.Log.Debug("cannot load messages for user", zap.Error(err))
+			logger.FromContext(ctx).Debug("cannot load messages for user", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -151,7 +175,7 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 			// обработаем поле Timezone запроса
 			tz, err := time.LoadLocation(req.Timezone)
 			if err != nil {
-				logger.Log.Debug("cannot parse timezone")
+				logger.FromContext(ctx).Debug("cannot parse timezone")
 				w.WriteHeader(http.StatusBadRequest)
 				return
 			}
@@ -178,8 +202,8 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 	// сериализуем ответ сервера
 	enc := json.NewEncoder(w)
 	if err := enc.Encode(resp); err != nil {
-		logger.Log.Debug("error encoding response", zap.Error(err))
+		logger.FromContext(ctx).Debug("error encoding response", zap.Error(err))
 		return
 	}
-	logger.Log.Debug("sending HTTP 200 response")
+	logger.FromContext(ctx).Debug("sending HTTP 200 response")
 }
@@ -0,0 +1,51 @@
+package main
+
+import "strings"
+
+// parseSendCommand разбирает команду вида "Отправь <логин> <текст сообщения>"
+// и возвращает логин адресата и текст сообщения.
+func parseSendCommand(command string) (username, message string) {
+	fields := strings.Fields(command)
+	if len(fields) < 2 {
+		return "", ""
+	}
+	username = strings.TrimPrefix(fields[1], "@")
+	if len(fields) > 2 {
+		message = strings.Join(fields[2:], " ")
+	}
+	return username, message
+}
+
+// parseReadCommand разбирает команду вида "Прочитай сообщение <номер>" и
+// возвращает индекс сообщения (с нуля) в списке доступных пользователю, то
+// есть <номер> минус один. Если номера нет, он не число или равен 0,
+// возвращается 0.
+func parseReadCommand(command string) int {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return 0
+	}
+	last := fields[len(fields)-1]
+
+	n := 0
+	for _, r := range last {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	if n == 0 {
+		return 0
+	}
+	return n - 1
+}
+
+// parseRegisterCommand разбирает команду вида "Зарегистрируй меня как <имя>"
+// и возвращает желаемое имя нового пользователя.
+func parseRegisterCommand(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"os"
+
+	"github.com/wurt83ow/alice-skill/internal/config"
 )
 
 var (
@@ -10,23 +12,56 @@ var (
 	flagLogLevel string
 	// переменная будет содержать параметры соединения с СУБД
 	flagDatabaseURI string
+	// переменная будет содержать адрес Redis, используемого очередью задач
+	flagRedisAddr string
+	// переменная будет содержать адрес административного листенера с
+	// метриками Prometheus и обработчиками /healthz, /readyz
+	flagAdminAddr string
+	// переменная будет содержать путь к YAML-файлу конфигурации
+	flagConfigFile string
+	// если установлена, навык применяет миграции схемы БД и завершает работу
+	flagMigrate bool
+
+	// flagsSet перечисляет имена флагов, фактически переданных пользователем
+	// в командной строке — используется для вычисления итоговой конфигурации
+	// с приоритетом flag > env > file > default
+	flagsSet = map[string]bool{}
 )
 
 func parseFlags() {
-	flag.StringVar(&flagRunAddr, "a", ":8080", "address and port to run server")
-	flag.StringVar(&flagLogLevel, "l", "info", "log level")
+	flag.StringVar(&flagRunAddr, "a", "", "address and port to run server")
+	flag.StringVar(&flagLogLevel, "l", "", "log level")
 	// обрабатываем аргумент -d
 	flag.StringVar(&flagDatabaseURI, "d", "", "database URI")
+	// обрабатываем аргумент -r
+	flag.StringVar(&flagRedisAddr, "r", "", "redis address")
+	// обрабатываем аргумент -m
+	flag.StringVar(&flagAdminAddr, "m", "", "admin address for metrics, healthz and readyz")
+	// обрабатываем аргумент -c
+	flag.StringVar(&flagConfigFile, "c", "", "path to YAML config file")
+	flag.BoolVar(&flagMigrate, "migrate", false, "apply pending database migrations and exit")
 	flag.Parse()
 
-	if envRunAddr := os.Getenv("RUN_ADDR"); envRunAddr != "" {
-		flagRunAddr = envRunAddr
-	}
-	if envLogLevel := os.Getenv("LOG_LEVEL"); envLogLevel != "" {
-		flagLogLevel = envLogLevel
+	// Visit (в отличие от VisitAll) обходит только флаги, заданные явно —
+	// это и даёт флагам наивысший приоритет перед значениями из файла/окружения
+	flag.Visit(func(f *flag.Flag) {
+		flagsSet[f.Name] = true
+	})
+
+	// как и остальные переменные окружения в internal/config, CONFIG_FILE не
+	// должна перебивать явно переданный флаг -c
+	if envConfigFile := os.Getenv("CONFIG_FILE"); envConfigFile != "" && !flagsSet["c"] {
+		flagConfigFile = envConfigFile
 	}
-	// обрабатываем переменную окружения DATABASE_URI
-	if envDatabaseURI := os.Getenv("DATABASE_URI"); envDatabaseURI != "" {
-		flagDatabaseURI = envDatabaseURI
+}
+
+func flagsAsConfig() config.Flags {
+	return config.Flags{
+		RunAddr:     flagRunAddr,
+		LogLevel:    flagLogLevel,
+		DatabaseURI: flagDatabaseURI,
+		RedisAddr:   flagRedisAddr,
+		AdminAddr:   flagAdminAddr,
+		Set:         flagsSet,
 	}
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+// compressWriter реализует интерфейс http.ResponseWriter и позволяет прозрачно для
+// сервера сжимать передаваемые данные и выставлять правильные HTTP-заголовки.
+type compressWriter struct {
+	w           http.ResponseWriter
+	zw          *gzip.Writer
+	wroteHeader bool
+}
+
+func newCompressWriter(w http.ResponseWriter) *compressWriter {
+	return &compressWriter{
+		w:  w,
+		zw: gzip.NewWriter(w),
+	}
+}
+
+func (c *compressWriter) Header() http.Header {
+	return c.w.Header()
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	// как и http.ResponseWriter, без явного WriteHeader считаем ответ 200 OK,
+	// чтобы заголовок Content-Encoding ушёл до сжатых данных
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	return c.zw.Write(p)
+}
+
+func (c *compressWriter) WriteHeader(statusCode int) {
+	c.wroteHeader = true
+	if statusCode < 300 {
+		c.w.Header().Set("Content-Encoding", "gzip")
+	}
+	c.w.WriteHeader(statusCode)
+}
+
+// Close закрывает gzip.Writer и досылает все данные из буфера.
+func (c *compressWriter) Close() error {
+	return c.zw.Close()
+}
+
+// compressReader реализует интерфейс io.ReadCloser и позволяет прозрачно для
+// сервера декомпрессировать получаемые от клиента данные.
+type compressReader struct {
+	r  io.ReadCloser
+	zr *gzip.Reader
+}
+
+func newCompressReader(r io.ReadCloser) (*compressReader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compressReader{
+		r:  r,
+		zr: zr,
+	}, nil
+}
+
+func (c compressReader) Read(p []byte) (n int, err error) {
+	return c.zr.Read(p)
+}
+
+func (c *compressReader) Close() error {
+	if err := c.r.Close(); err != nil {
+		return err
+	}
+	return c.zr.Close()
+}
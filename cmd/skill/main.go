@@ -2,16 +2,36 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"strings"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/wurt83ow/alice-skill/internal/config"
 	"github.com/wurt83ow/alice-skill/internal/logger"
+	"github.com/wurt83ow/alice-skill/internal/metrics"
+	"github.com/wurt83ow/alice-skill/internal/queue"
+	queuememory "github.com/wurt83ow/alice-skill/internal/queue/memory"
+	queueredis "github.com/wurt83ow/alice-skill/internal/queue/redis"
+	"github.com/wurt83ow/alice-skill/internal/store"
+	"github.com/wurt83ow/alice-skill/internal/store/bolt"
+	"github.com/wurt83ow/alice-skill/internal/store/memory"
 	"github.com/wurt83ow/alice-skill/internal/store/pg"
+	"github.com/wurt83ow/alice-skill/internal/worker"
 	"go.uber.org/zap"
 )
 
+// pinger проверяет доступность зависимости хранилища — реализуется pg.Store,
+// единственным бэкендом, обращающимся к внешней СУБД.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// memoryQueueBuffer — размер буфера канала задач для queue.driver: memory.
+const memoryQueueBuffer = 100
+
 func gzipMiddleware(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// по умолчанию устанавливаем оригинальный http.ResponseWriter как тот,
@@ -60,20 +80,121 @@ func main() {
 }
 
 func run() error {
-	if err := logger.Initialize(flagLogLevel); err != nil {
+	// собираем итоговую конфигурацию: flag > env > file > default — так
+	// операторы могут держать один config.yaml на окружение и точечно
+	// переопределять отдельные параметры флагом или переменной окружения
+	cfg, err := config.Load(flagConfigFile, flagsAsConfig())
+	if err != nil {
+		return err
+	}
+
+	if err := logger.Initialize(cfg.LogLevel); err != nil {
+		return err
+	}
+
+	// флаг -migrate только накатывает миграции схемы PostgreSQL и завершает
+	// работу, не поднимая сервер; остальные драйверы хранилища в миграциях не нуждаются
+	if flagMigrate {
+		conn, err := sql.Open("pgx", cfg.DatabaseURI)
+		if err != nil {
+			return err
+		}
+
+		logger.Log.Info("Applying database migrations")
+		return pg.NewStore(conn).Migrate(context.Background())
+	}
+
+	s, err := newStore(cfg)
+	if err != nil {
 		return err
 	}
 
-	// создаём соединение к СУБД PostgreSQL с помощью аргумента командной строки
-	conn, err := sql.Open("pgx", flagDatabaseURI)
+	queueClient, err := newQueueClient(cfg, s)
 	if err != nil {
 		return err
 	}
 
-	// создаём экземпляр приложения, передавая реализацию хранилища pg в качестве внешней зависимости
-	appInstance := newApp(pg.NewStore(conn))
+	// создаём экземпляр приложения, передавая реализации хранилища и очереди в качестве внешних зависимостей
+	appInstance := newApp(s, queueClient)
+
+	// административный листенер поднимаем отдельно от основного сервера,
+	// чтобы метрики и проверки состояния были доступны даже при проблемах
+	// с обработкой пользовательского трафика
+	go func() {
+		logger.Log.Info("Running admin server", zap.String("address", cfg.AdminAddr))
+		if err := http.ListenAndServe(cfg.AdminAddr, adminMux(s)); err != nil {
+			logger.Log.Error("admin server stopped", zap.Error(err))
+		}
+	}()
 
-	logger.Log.Info("Running server", zap.String("address", flagRunAddr))
+	logger.Log.Info("Running server", zap.String("address", cfg.RunAddr))
 	// обернём хендлер webhook в middleware с логгированием и поддержкой gzip
-	return http.ListenAndServe(flagRunAddr, logger.RequestLogger(gzipMiddleware(appInstance.webhook)))
+	return http.ListenAndServe(cfg.RunAddr, logger.RequestLogger(gzipMiddleware(appInstance.webhook)))
+}
+
+// adminMux собирает обработчики административного листенера: экспозицию
+// метрик Prometheus и проверки состояния /healthz, /readyz.
+func adminMux(s store.Store) *http.ServeMux {
+	// readyz зависит от внешней СУБД только для бэкендов, которые её
+	// используют — остальные готовы обслуживать трафик сразу
+	ping := func(ctx context.Context) error { return nil }
+	if p, ok := s.(pinger); ok {
+		ping = p.Ping
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", metrics.Healthz)
+	mux.HandleFunc("/readyz", metrics.Readyz(ping))
+	return mux
+}
+
+// newQueueClient выбирает реализацию queue.Client по конфигурации
+// queue.driver. Для драйвера "memory" задачи доставки сообщений
+// обрабатываются тем же процессом — через queuememory.Server, запущенный
+// в фоне с тем же обработчиком, что использует cmd/worker, — поэтому навык
+// можно запустить совсем без Redis.
+func newQueueClient(cfg config.Config, s store.Store) (queue.Client, error) {
+	switch cfg.Queue.Driver {
+	case "", "redis":
+		return queueredis.NewClient(cfg.RedisAddr), nil
+	case "memory":
+		// буфер канала задач произвольный — очередь нужна только для развязки
+		// обработчика вебхука от сохранения сообщения, а не для накопления нагрузки
+		client := queuememory.NewClient(memoryQueueBuffer)
+		srv := queuememory.NewServer(client)
+		srv.RegisterHandler(queue.TypeSendMessage, worker.HandleSendMessage(s))
+
+		go func() {
+			if err := srv.Run(context.Background()); err != nil {
+				logger.Log.Error("in-process queue worker stopped", zap.Error(err))
+			}
+		}()
+
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown queue driver %q", cfg.Queue.Driver)
+	}
+}
+
+// newStore выбирает реализацию store.Store по конфигурации storage.driver.
+func newStore(cfg config.Config) (store.Store, error) {
+	switch cfg.Storage.Driver {
+	case "", "pg":
+		conn, err := sql.Open("pgx", cfg.DatabaseURI)
+		if err != nil {
+			return nil, err
+		}
+		conn.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+		conn.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+		conn.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+
+		return pg.NewStore(conn), nil
+	case "memory":
+		return memory.NewStore(), nil
+	case "bolt":
+		return bolt.NewStore(cfg.Storage.Path)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
 }
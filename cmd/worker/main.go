@@ -0,0 +1,77 @@
+// пакеты исполняемых приложений должны называться main
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/wurt83ow/alice-skill/internal/logger"
+	"github.com/wurt83ow/alice-skill/internal/queue"
+	queueredis "github.com/wurt83ow/alice-skill/internal/queue/redis"
+	"github.com/wurt83ow/alice-skill/internal/store/pg"
+	"github.com/wurt83ow/alice-skill/internal/worker"
+	"go.uber.org/zap"
+)
+
+var (
+	flagRedisAddr   string
+	flagDatabaseURI string
+	flagLogLevel    string
+	flagConcurrency int
+)
+
+func parseFlags() {
+	flag.StringVar(&flagRedisAddr, "r", "localhost:6379", "redis address")
+	flag.StringVar(&flagDatabaseURI, "d", "", "database URI")
+	flag.StringVar(&flagLogLevel, "l", "info", "log level")
+	flag.IntVar(&flagConcurrency, "c", 10, "number of tasks processed concurrently")
+	flag.Parse()
+
+	if envRedisAddr := os.Getenv("REDIS_ADDR"); envRedisAddr != "" {
+		flagRedisAddr = envRedisAddr
+	}
+	if envDatabaseURI := os.Getenv("DATABASE_URI"); envDatabaseURI != "" {
+		flagDatabaseURI = envDatabaseURI
+	}
+	if envLogLevel := os.Getenv("LOG_LEVEL"); envLogLevel != "" {
+		flagLogLevel = envLogLevel
+	}
+}
+
+// функция main вызывается автоматически при запуске приложения
+func main() {
+	parseFlags()
+
+	if err := run(); err != nil {
+		panic(err)
+	}
+}
+
+func run() error {
+	if err := logger.Initialize(flagLogLevel); err != nil {
+		return err
+	}
+
+	// создаём соединение к СУБД PostgreSQL с помощью аргумента командной строки
+	conn, err := sql.Open("pgx", flagDatabaseURI)
+	if err != nil {
+		return err
+	}
+
+	s := pg.NewStore(conn)
+
+	srv := queueredis.NewServer(flagRedisAddr, flagConcurrency)
+	srv.RegisterHandler(queue.TypeSendMessage, worker.HandleSendMessage(s))
+
+	// останавливаем воркер по сигналу, чтобы текущие задачи успели завершиться
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	logger.Log.Info("Running worker", zap.String("redis", flagRedisAddr))
+	return srv.Run(ctx)
+}
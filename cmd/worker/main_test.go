@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/alice-skill/internal/queue"
+	queuememory "github.com/wurt83ow/alice-skill/internal/queue/memory"
+	storememory "github.com/wurt83ow/alice-skill/internal/store/memory"
+	"github.com/wurt83ow/alice-skill/internal/worker"
+)
+
+// TestHandleSendMessage прогоняет весь путь enqueue -> очередь -> обработчик:
+// задача с ключом идемпотентности, поставленная в очередь, должна быть
+// сохранена обработчиком в хранилище, а повторная доставка той же задачи —
+// не создавать дубликат сообщения.
+func TestHandleSendMessage(t *testing.T) {
+	ctx := context.Background()
+
+	s := storememory.NewStore()
+	require.NoError(t, s.RegisterUser(ctx, "sender-id", "bob"))
+	require.NoError(t, s.RegisterUser(ctx, "recepient-id", "alice"))
+
+	client := queuememory.NewClient(1)
+	srv := queuememory.NewServer(client)
+	srv.RegisterHandler(queue.TypeSendMessage, worker.HandleSendMessage(s))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = srv.Run(runCtx)
+		close(done)
+	}()
+
+	payload, err := json.Marshal(queue.SendMessagePayload{
+		RequestID:      "req-1",
+		Sender:         "sender-id",
+		RecepientID:    "recepient-id",
+		Text:           "hello",
+		SentAt:         time.Now(),
+		IdempotencyKey: "idem-1",
+	})
+	require.NoError(t, err)
+	task := queue.Task{Type: queue.TypeSendMessage, Payload: payload}
+
+	require.NoError(t, client.Enqueue(runCtx, task))
+	require.Eventually(t, func() bool {
+		messages, err := s.ListMessages(ctx, "recepient-id")
+		return err == nil && len(messages) == 1
+	}, time.Second, time.Millisecond)
+
+	// повторная доставка той же задачи (например, после ретрая Asynq) не
+	// должна привести к дубликату сообщения; ошибку обработчика
+	// queuememory.Server не возвращает, поэтому здесь она не проверяется
+	require.NoError(t, client.Enqueue(runCtx, task))
+	require.Never(t, func() bool {
+		messages, _ := s.ListMessages(ctx, "recepient-id")
+		return len(messages) > 1
+	}, 100*time.Millisecond, 10*time.Millisecond)
+}
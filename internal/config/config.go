@@ -0,0 +1,160 @@
+// Package config собирает конфигурацию навыка из YAML-файла, переменных
+// окружения и флагов командной строки с приоритетом flag > env > file >
+// default, так что оператор может развернуть один config.yaml на окружение
+// и переопределить отдельные параметры флагом или переменной окружения при
+// необходимости.
+package config
+
+import (
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config описывает конфигурацию процесса cmd/skill.
+type Config struct {
+	// RunAddr — адрес и порт, на которых навык принимает HTTP-запросы.
+	RunAddr string `yaml:"run_addr"`
+	// LogLevel — уровень логирования (см. zap.ParseAtomicLevel).
+	LogLevel string `yaml:"log_level"`
+	// DatabaseURI — строка подключения к PostgreSQL.
+	DatabaseURI string `yaml:"database_uri"`
+	// RedisAddr — адрес Redis, используемого очередью задач.
+	RedisAddr string `yaml:"redis_addr"`
+	// AdminAddr — адрес и порт административного листенера с метриками
+	// Prometheus и обработчиками /healthz, /readyz.
+	AdminAddr string `yaml:"admin_addr"`
+
+	Database DatabaseConfig `yaml:"database"`
+	// Storage выбирает реализацию store.Store, используемую cmd/skill
+	// (см. newStore в cmd/skill/main.go).
+	Storage StorageConfig `yaml:"storage"`
+	// Queue выбирает реализацию queue.Client, используемую cmd/skill
+	// (см. newQueueClient в cmd/skill/main.go).
+	Queue QueueConfig `yaml:"queue"`
+}
+
+// DatabaseConfig описывает параметры пула соединений с СУБД.
+type DatabaseConfig struct {
+	// MaxOpenConns ограничивает число одновременно открытых соединений.
+	MaxOpenConns int `yaml:"max_open_conns"`
+	// MaxIdleConns ограничивает число простаивающих соединений в пуле.
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// ConnMaxLifetime ограничивает время жизни одного соединения.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// StorageConfig описывает выбор реализации store.Store.
+type StorageConfig struct {
+	// Driver выбирает реализацию хранилища: "pg", "memory" или "bolt".
+	Driver string `yaml:"driver"`
+	// Path — путь к файлу БД для драйвера "bolt".
+	Path string `yaml:"path"`
+}
+
+// QueueConfig описывает выбор реализации queue.Client.
+type QueueConfig struct {
+	// Driver выбирает реализацию очереди: "redis" или "memory". Драйвер
+	// "memory" даёт возможность запустить навык без Redis: cmd/skill
+	// обрабатывает задачи доставки сообщений тем же процессом.
+	Driver string `yaml:"driver"`
+}
+
+// Default возвращает конфигурацию со значениями по умолчанию. RunAddr и
+// LogLevel совпадают с теми, что раньше были жёстко зашиты в
+// cmd/skill/flags.go; остальные значения заданы здесь.
+func Default() Config {
+	return Config{
+		RunAddr:   ":8080",
+		LogLevel:  "info",
+		RedisAddr: "localhost:6379",
+		AdminAddr: ":9090",
+		Database: DatabaseConfig{
+			MaxOpenConns:    10,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: time.Hour,
+		},
+		Storage: StorageConfig{Driver: "pg", Path: "alice-skill.db"},
+		Queue:   QueueConfig{Driver: "redis"},
+	}
+}
+
+// Flags содержит значения флагов командной строки cmd/skill. Флаги имеют
+// наивысший приоритет при формировании итоговой конфигурации, но только
+// если были явно переданы пользователем — Set перечисляет их имена.
+type Flags struct {
+	RunAddr     string
+	LogLevel    string
+	DatabaseURI string
+	RedisAddr   string
+	AdminAddr   string
+	Set         map[string]bool
+}
+
+// Load формирует итоговую конфигурацию: берёт значения по умолчанию,
+// накладывает поверх них файл configFile (если путь не пуст), затем
+// переменные окружения и, наконец, явно переданные флаги.
+func Load(configFile string, flags Flags) (Config, error) {
+	cfg := Default()
+
+	if err := applyFile(configFile, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	applyEnv(&cfg)
+	applyFlags(flags, &cfg)
+
+	return cfg, nil
+}
+
+// applyFile читает YAML-файл по указанному пути и накладывает его поверх
+// cfg. Пустой path не считается ошибкой — cfg остаётся без изменений.
+func applyFile(path string, cfg *Config) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, cfg)
+}
+
+func applyEnv(cfg *Config) {
+	if v := os.Getenv("RUN_ADDR"); v != "" {
+		cfg.RunAddr = v
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.LogLevel = v
+	}
+	if v := os.Getenv("DATABASE_URI"); v != "" {
+		cfg.DatabaseURI = v
+	}
+	if v := os.Getenv("REDIS_ADDR"); v != "" {
+		cfg.RedisAddr = v
+	}
+	if v := os.Getenv("ADMIN_ADDR"); v != "" {
+		cfg.AdminAddr = v
+	}
+}
+
+func applyFlags(flags Flags, cfg *Config) {
+	if flags.Set["a"] {
+		cfg.RunAddr = flags.RunAddr
+	}
+	if flags.Set["l"] {
+		cfg.LogLevel = flags.LogLevel
+	}
+	if flags.Set["d"] {
+		cfg.DatabaseURI = flags.DatabaseURI
+	}
+	if flags.Set["r"] {
+		cfg.RedisAddr = flags.RedisAddr
+	}
+	if flags.Set["m"] {
+		cfg.AdminAddr = flags.AdminAddr
+	}
+}
@@ -0,0 +1,144 @@
+// Package logger отвечает за структурированное JSON-логирование навыка и за
+// сквозную передачу идентификатора запроса (correlation ID) через
+// context.Context.
+package logger
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Log будет доступен всему коду как синглтон.
+// Никакой код навыка, кроме функции Initialize, не должен модифицировать эту переменную.
+// Код навыка, использующий журналирование, должен обращаться к переменной Log
+// как к своему собственному пакетному неэкспортируемому полю, либо, если
+// доступен context.Context запроса, получать логгер через FromContext.
+var Log *zap.Logger = zap.NewNop()
+
+// requestIDHeader — заголовок, в котором передаётся и возвращается
+// идентификатор запроса.
+const requestIDHeader = "X-Request-Id"
+
+type contextKey string
+
+// requestIDKey — ключ, под которым идентификатор запроса хранится в context.Context.
+const requestIDKey contextKey = "requestID"
+
+// Initialize инициализирует синглтон логера с необходимым уровнем логирования.
+// Логи всегда пишутся в формате JSON, что упрощает их разбор в production.
+func Initialize(level string) error {
+	lvl, err := zap.ParseAtomicLevel(level)
+	if err != nil {
+		return err
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = lvl
+	cfg.Encoding = "json"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	zl, err := cfg.Build()
+	if err != nil {
+		return err
+	}
+	Log = zl
+	return nil
+}
+
+// WithRequestID возвращает производный от ctx контекст с привязанным
+// идентификатором запроса.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID возвращает идентификатор запроса, ранее сохранённый в ctx
+// функцией WithRequestID, либо пустую строку.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// FromContext возвращает *zap.Logger с полем request_id, привязанным из ctx,
+// если он там есть. Если идентификатора в ctx нет, возвращается Log как есть.
+func FromContext(ctx context.Context) *zap.Logger {
+	requestID := RequestID(ctx)
+	if requestID == "" {
+		return Log
+	}
+	return Log.With(zap.String("request_id", requestID))
+}
+
+// responseData собирает сведения об ответе сервера для логирования.
+type (
+	responseData struct {
+		status int
+		size   int
+	}
+
+	loggingResponseWriter struct {
+		http.ResponseWriter
+		responseData *responseData
+	}
+)
+
+func (r *loggingResponseWriter) Write(b []byte) (int, error) {
+	size, err := r.ResponseWriter.Write(b)
+	r.responseData.size += size
+	return size, err
+}
+
+func (r *loggingResponseWriter) WriteHeader(statusCode int) {
+	r.ResponseWriter.WriteHeader(statusCode)
+	r.responseData.status = statusCode
+}
+
+// RequestLogger оборачивает хендлер middleware'ом, который:
+//   - извлекает X-Request-Id из заголовка запроса или генерирует новый UUIDv4,
+//     если заголовок отсутствует;
+//   - кладёт идентификатор запроса в context.Context, чтобы все последующие
+//     вызовы (хендлер, обращения к хранилищу) могли получить через него
+//     логгер с помощью FromContext;
+//   - возвращает идентификатор запроса клиенту в заголовке ответа;
+//   - логирует сведения о запросе и ответе той же записью, что и остальной
+//     код обработки запроса.
+func RequestLogger(h http.HandlerFunc) http.HandlerFunc {
+	logFn := func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := WithRequestID(r.Context(), requestID)
+		r = r.WithContext(ctx)
+
+		responseData := &responseData{
+			status: 0,
+			size:   0,
+		}
+		lw := loggingResponseWriter{
+			ResponseWriter: w,
+			responseData:   responseData,
+		}
+
+		h.ServeHTTP(&lw, r)
+
+		duration := time.Since(start)
+
+		FromContext(ctx).Info("got incoming HTTP request",
+			zap.String("uri", r.RequestURI),
+			zap.String("method", r.Method),
+			zap.Duration("duration", duration),
+			zap.Int("status", responseData.status),
+			zap.Int("size", responseData.size),
+		)
+	}
+	return logFn
+}
@@ -0,0 +1,67 @@
+// Package metrics регистрирует метрики Prometheus навыка и предоставляет
+// обработчики /healthz и /readyz для отдельного административного листенера.
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// WebhookRequestsTotal считает запросы, принятые обработчиком вебхука навыка.
+var WebhookRequestsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "alice_skill",
+	Name:      "webhook_requests_total",
+	Help:      "Total number of webhook requests handled.",
+})
+
+// CommandDuration измеряет длительность обработки команды пользователя, с
+// меткой command — именем ветки switch в app.webhook ("send", "read",
+// "register" или "default").
+var CommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "alice_skill",
+	Name:      "webhook_command_duration_seconds",
+	Help:      "Duration of handling a single webhook command.",
+}, []string{"command"})
+
+// StoreQueryDuration измеряет длительность запросов pg.Store, с метками
+// query (имя метода хранилища) и error ("true"/"false").
+var StoreQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "alice_skill",
+	Name:      "store_query_duration_seconds",
+	Help:      "Duration of pg.Store queries to PostgreSQL.",
+}, []string{"query", "error"})
+
+// ObserveCommand засекает время выполнения одной команды webhook-обработчика.
+// Вызывать через defer сразу при входе в соответствующую ветку switch:
+//
+//	defer metrics.ObserveCommand("send")()
+func ObserveCommand(command string) func() {
+	timer := prometheus.NewTimer(CommandDuration.WithLabelValues(command))
+	return func() { timer.ObserveDuration() }
+}
+
+// Handler возвращает обработчик экспозиции метрик в формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Healthz сигнализирует о том, что процесс жив, не обращаясь к внешним зависимостям.
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// Readyz возвращает обработчик готовности, который считает навык готовым
+// принимать трафик, только если ping успешно проверяет соединение с СУБД.
+func Readyz(ping func(ctx context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := ping(r.Context()); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
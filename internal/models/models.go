@@ -15,7 +15,13 @@ type Request struct {
 }
 
 type Session struct {
-	New bool `json:"new"`
+	New  bool `json:"new"`
+	User User `json:"user"`
+}
+
+// User описывает данные пользователя Алисы, авторизованного в навыке.
+type User struct {
+	UserID string `json:"user_id"`
 }
 
 // SimpleUtterance описывает команду, полученную в запросе типа SimpleUtterance.
@@ -0,0 +1,87 @@
+// Package memory содержит реализацию queue.Client/queue.Server поверх
+// канала в памяти — без внешних зависимостей, для тестов и локальной
+// разработки без Redis.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wurt83ow/alice-skill/internal/queue"
+)
+
+// Client — реализация queue.Client, кладущая задачи в канал в памяти.
+type Client struct {
+	tasks chan queue.Task
+}
+
+// NewClient возвращает новый экземпляр Client с буфером на buffer задач.
+func NewClient(buffer int) *Client {
+	return &Client{tasks: make(chan queue.Task, buffer)}
+}
+
+// Enqueue кладёт задачу в канал. Параметры из opts (в частности, отложенный
+// запуск) этой реализацией не поддерживаются и игнорируются.
+func (c *Client) Enqueue(ctx context.Context, task queue.Task, opts ...queue.Option) error {
+	select {
+	case c.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close закрывает канал задач.
+func (c *Client) Close() error {
+	close(c.tasks)
+	return nil
+}
+
+// Server — реализация queue.Server, читающая задачи из канала Client.
+type Server struct {
+	tasks chan queue.Task
+
+	mu       sync.Mutex
+	handlers map[string]queue.Handler
+}
+
+// NewServer возвращает Server, читающий задачи из канала, в который пишет c.
+func NewServer(c *Client) *Server {
+	return &Server{
+		tasks:    c.tasks,
+		handlers: make(map[string]queue.Handler),
+	}
+}
+
+// RegisterHandler связывает тип задачи с её обработчиком.
+func (s *Server) RegisterHandler(taskType string, h queue.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[taskType] = h
+}
+
+// Run читает задачи из канала и передаёт их зарегистрированным обработчикам,
+// пока канал не закроется или ctx не будет отменён.
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		select {
+		case task, ok := <-s.tasks:
+			if !ok {
+				return nil
+			}
+
+			s.mu.Lock()
+			h, found := s.handlers[task.Type]
+			s.mu.Unlock()
+
+			if !found {
+				continue
+			}
+			// в тестовой реализации ошибки обработчика не приводят к повторной
+			// постановке задачи в очередь
+			_ = h(ctx, task)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
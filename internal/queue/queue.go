@@ -0,0 +1,69 @@
+// Package queue определяет абстракцию очереди фоновых задач, через которую
+// обработчик вебхука откладывает долгие операции (например, доставку
+// сообщений) вместо того, чтобы выполнять их синхронно в рамках HTTP-запроса.
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// Типы задач, которые умеет обрабатывать навык.
+const (
+	// TypeSendMessage — задача на сохранение нового сообщения получателю.
+	TypeSendMessage = "send_message"
+)
+
+// SendMessagePayload описывает данные задачи TypeSendMessage.
+type SendMessagePayload struct {
+	RequestID      string    `json:"request_id"`
+	Sender         string    `json:"sender"`
+	RecepientID    string    `json:"recepient_id"`
+	Text           string    `json:"text"`
+	SentAt         time.Time `json:"sent_at"`
+	IdempotencyKey string    `json:"idempotency_key"`
+}
+
+// Task описывает задачу, помещаемую в очередь.
+type Task struct {
+	// Type — тип задачи, по которому Server выбирает обработчик.
+	Type string
+	// Payload — сериализованные данные задачи.
+	Payload []byte
+}
+
+// TaskOptions содержит необязательные параметры выполнения задачи.
+type TaskOptions struct {
+	// ProcessAt указывает, когда задача должна быть обработана. Нулевое
+	// значение означает "как можно скорее".
+	ProcessAt time.Time
+}
+
+// Option задаёт один из параметров TaskOptions.
+type Option func(*TaskOptions)
+
+// ProcessIn откладывает выполнение задачи на указанный интервал — пригодится,
+// например, для команд вида "Отправь через 5 минут ...".
+func ProcessIn(d time.Duration) Option {
+	return func(o *TaskOptions) {
+		o.ProcessAt = time.Now().Add(d)
+	}
+}
+
+// Client ставит задачи в очередь на асинхронную обработку.
+type Client interface {
+	Enqueue(ctx context.Context, task Task, opts ...Option) error
+	Close() error
+}
+
+// Handler обрабатывает задачу конкретного типа.
+type Handler func(ctx context.Context, task Task) error
+
+// Server разбирает задачи из очереди и передаёт их зарегистрированным
+// обработчикам.
+type Server interface {
+	// RegisterHandler связывает тип задачи с её обработчиком.
+	RegisterHandler(taskType string, h Handler)
+	// Run запускает обработку задач и блокируется до отмены ctx или ошибки.
+	Run(ctx context.Context) error
+}
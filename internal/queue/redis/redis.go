@@ -0,0 +1,81 @@
+// Package redis содержит реализацию queue.Client/queue.Server поверх Redis
+// с помощью библиотеки Asynq.
+package redis
+
+import (
+	"context"
+
+	"github.com/hibiken/asynq"
+	"github.com/wurt83ow/alice-skill/internal/queue"
+)
+
+// Client — реализация queue.Client, ставящая задачи в очередь в Redis.
+type Client struct {
+	client *asynq.Client
+}
+
+// NewClient возвращает Client, подключённый к Redis по адресу addr.
+func NewClient(addr string) *Client {
+	return &Client{client: asynq.NewClient(asynq.RedisClientOpt{Addr: addr})}
+}
+
+// Enqueue ставит задачу в очередь. Задача выполняется с повторными попытками
+// и экспоненциальной задержкой между ними; после исчерпания попыток Asynq
+// переносит её в архив задач, то есть играет роль dead-letter очереди.
+func (c *Client) Enqueue(ctx context.Context, task queue.Task, opts ...queue.Option) error {
+	var o queue.TaskOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	t := asynq.NewTask(task.Type, task.Payload)
+
+	asynqOpts := []asynq.Option{asynq.MaxRetry(5)}
+	if !o.ProcessAt.IsZero() {
+		asynqOpts = append(asynqOpts, asynq.ProcessAt(o.ProcessAt))
+	}
+
+	_, err := c.client.EnqueueContext(ctx, t, asynqOpts...)
+	return err
+}
+
+// Close закрывает соединение с Redis.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Server — реализация queue.Server, разбирающая задачи из очереди в Redis.
+type Server struct {
+	srv *asynq.Server
+	mux *asynq.ServeMux
+}
+
+// NewServer возвращает Server, подключённый к Redis по адресу addr и
+// обрабатывающий до concurrency задач одновременно.
+func NewServer(addr string, concurrency int) *Server {
+	return &Server{
+		srv: asynq.NewServer(
+			asynq.RedisClientOpt{Addr: addr},
+			asynq.Config{Concurrency: concurrency},
+		),
+		mux: asynq.NewServeMux(),
+	}
+}
+
+// RegisterHandler связывает тип задачи с её обработчиком.
+func (s *Server) RegisterHandler(taskType string, h queue.Handler) {
+	s.mux.HandleFunc(taskType, func(ctx context.Context, t *asynq.Task) error {
+		return h(ctx, queue.Task{Type: t.Type(), Payload: t.Payload()})
+	})
+}
+
+// Run запускает обработку задач и блокируется до отмены ctx.
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.srv.Start(s.mux); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	s.srv.Shutdown()
+	return nil
+}
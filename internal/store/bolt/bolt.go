@@ -0,0 +1,197 @@
+// Package bolt содержит реализацию store.Store поверх bbolt — однофайловой
+// встраиваемой БД, которой достаточно для небольших одиночных развёртываний
+// навыка без внешней СУБД.
+package bolt
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/wurt83ow/alice-skill/internal/store"
+)
+
+var (
+	usersBucket       = []byte("users")       // userID -> username
+	usernamesBucket   = []byte("usernames")   // username -> userID
+	messagesBucket    = []byte("messages")    // big-endian id -> storedMessage
+	idempotencyBucket = []byte("idempotency") // idempotency key -> {}
+)
+
+// Store — реализация store.Store поверх файла bbolt.
+type Store struct {
+	db *bolt.DB
+}
+
+// NewStore открывает (создавая при необходимости) файл БД по указанному пути.
+func NewStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{usersBucket, usernamesBucket, messagesBucket, idempotencyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close закрывает файл БД.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) RegisterUser(ctx context.Context, userID, username string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		usernames := tx.Bucket(usernamesBucket)
+		if usernames.Get([]byte(username)) != nil {
+			return store.ErrConflict
+		}
+
+		if err := usernames.Put([]byte(username), []byte(userID)); err != nil {
+			return err
+		}
+		return tx.Bucket(usersBucket).Put([]byte(userID), []byte(username))
+	})
+}
+
+func (s *Store) FindRecepient(ctx context.Context, username string) (userID string, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(usernamesBucket).Get([]byte(username))
+		if v == nil {
+			// как и pg.Store, сигнализируем об отсутствии записи через
+			// sql.ErrNoRows, чтобы вызывающему коду не приходилось различать бэкенды
+			return sql.ErrNoRows
+		}
+		userID = string(v)
+		return nil
+	})
+	return userID, err
+}
+
+// storedMessage — представление store.Message, в котором хранится внутренний
+// идентификатор отправителя вместо его имени; имя разрешается при чтении.
+type storedMessage struct {
+	Sender    string    `json:"sender"`
+	Recepient string    `json:"recepient"`
+	Payload   string    `json:"payload"`
+	Time      time.Time `json:"time"`
+}
+
+func (s *Store) SaveMessage(ctx context.Context, userID string, msg store.Message) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if msg.Key != "" {
+			idempotency := tx.Bucket(idempotencyBucket)
+			if idempotency.Get([]byte(msg.Key)) != nil {
+				return store.ErrConflict
+			}
+			if err := idempotency.Put([]byte(msg.Key), []byte{1}); err != nil {
+				return err
+			}
+		}
+
+		messages := tx.Bucket(messagesBucket)
+		id, err := messages.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(storedMessage{
+			Sender:    msg.Sender,
+			Recepient: userID,
+			Payload:   msg.Payload,
+			Time:      msg.Time,
+		})
+		if err != nil {
+			return err
+		}
+
+		return messages.Put(itob(id), data)
+	})
+}
+
+func (s *Store) ListMessages(ctx context.Context, userID string) ([]store.Message, error) {
+	var messages []store.Message
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		users := tx.Bucket(usersBucket)
+
+		// bbolt отдаёт ключи в порядке байтового сравнения; big-endian кодирование
+		// id в itob делает этот порядок совпадающим с порядком возрастания id
+		return tx.Bucket(messagesBucket).ForEach(func(k, v []byte) error {
+			var m storedMessage
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			if m.Recepient != userID {
+				return nil
+			}
+
+			username := users.Get([]byte(m.Sender))
+			if username == nil {
+				// как и INNER JOIN в pg.Store, пропускаем сообщения от
+				// отправителя, которого больше нет среди пользователей
+				return nil
+			}
+
+			messages = append(messages, store.Message{
+				ID:     int64(btoi(k)),
+				Sender: string(username),
+				Time:   m.Time,
+			})
+			return nil
+		})
+	})
+
+	return messages, err
+}
+
+func (s *Store) GetMessage(ctx context.Context, id int64) (*store.Message, error) {
+	var msg *store.Message
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(messagesBucket).Get(itob(uint64(id)))
+		if v == nil {
+			return sql.ErrNoRows
+		}
+
+		var m storedMessage
+		if err := json.Unmarshal(v, &m); err != nil {
+			return err
+		}
+
+		username := tx.Bucket(usersBucket).Get([]byte(m.Sender))
+		if username == nil {
+			return sql.ErrNoRows
+		}
+
+		msg = &store.Message{ID: id, Sender: string(username), Payload: m.Payload, Time: m.Time}
+		return nil
+	})
+
+	return msg, err
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
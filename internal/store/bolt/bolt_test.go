@@ -0,0 +1,20 @@
+package bolt_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/alice-skill/internal/store"
+	"github.com/wurt83ow/alice-skill/internal/store/bolt"
+	"github.com/wurt83ow/alice-skill/internal/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		s, err := bolt.NewStore(filepath.Join(t.TempDir(), "alice-skill.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	})
+}
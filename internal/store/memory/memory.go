@@ -0,0 +1,124 @@
+// Package memory содержит потокобезопасную in-memory реализацию store.Store,
+// предназначенную для тестов и локальной разработки без развёрнутой БД.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/wurt83ow/alice-skill/internal/store"
+)
+
+// Store — реализация store.Store, хранящая данные в картах в памяти процесса.
+// Данные не переживают перезапуск. Нулевое значение не готово к использованию —
+// создавайте через NewStore.
+type Store struct {
+	mu sync.RWMutex
+
+	usernameByUserID map[string]string
+	userIDByUsername map[string]string
+
+	nextMessageID   int64
+	messages        map[int64]store.Message
+	messageUserID   map[int64]string
+	idempotencyKeys map[string]bool
+}
+
+// NewStore возвращает новый пустой экземпляр in-memory хранилища.
+func NewStore() *Store {
+	return &Store{
+		usernameByUserID: make(map[string]string),
+		userIDByUsername: make(map[string]string),
+		messages:         make(map[int64]store.Message),
+		messageUserID:    make(map[int64]string),
+		idempotencyKeys:  make(map[string]bool),
+	}
+}
+
+func (s *Store) RegisterUser(ctx context.Context, userID, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.userIDByUsername[username]; exists {
+		return store.ErrConflict
+	}
+
+	s.usernameByUserID[userID] = username
+	s.userIDByUsername[username] = userID
+	return nil
+}
+
+func (s *Store) FindRecepient(ctx context.Context, username string) (userID string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	userID, ok := s.userIDByUsername[username]
+	if !ok {
+		// как и pg.Store, сигнализируем об отсутствии записи через sql.ErrNoRows,
+		// чтобы вызывающему коду не приходилось различать бэкенды
+		return "", sql.ErrNoRows
+	}
+	return userID, nil
+}
+
+func (s *Store) SaveMessage(ctx context.Context, userID string, msg store.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.Key != "" {
+		if s.idempotencyKeys[msg.Key] {
+			return store.ErrConflict
+		}
+		s.idempotencyKeys[msg.Key] = true
+	}
+
+	s.nextMessageID++
+	msg.ID = s.nextMessageID
+	s.messages[msg.ID] = msg
+	s.messageUserID[msg.ID] = userID
+	return nil
+}
+
+func (s *Store) ListMessages(ctx context.Context, userID string) ([]store.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var messages []store.Message
+	for id, recepient := range s.messageUserID {
+		if recepient != userID {
+			continue
+		}
+
+		msg := s.messages[id]
+		username, ok := s.usernameByUserID[msg.Sender]
+		if !ok {
+			// как и INNER JOIN в pg.Store, пропускаем сообщения от отправителя,
+			// которого больше нет среди зарегистрированных пользователей
+			continue
+		}
+
+		messages = append(messages, store.Message{ID: msg.ID, Sender: username, Time: msg.Time})
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+	return messages, nil
+}
+
+func (s *Store) GetMessage(ctx context.Context, id int64) (*store.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	msg, ok := s.messages[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	username, ok := s.usernameByUserID[msg.Sender]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &store.Message{ID: msg.ID, Sender: username, Payload: msg.Payload, Time: msg.Time}, nil
+}
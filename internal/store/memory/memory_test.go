@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/wurt83ow/alice-skill/internal/store"
+	"github.com/wurt83ow/alice-skill/internal/store/memory"
+	"github.com/wurt83ow/alice-skill/internal/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		return memory.NewStore()
+	})
+}
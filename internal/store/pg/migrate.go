@@ -0,0 +1,145 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wurt83ow/alice-skill/internal/logger"
+	"github.com/wurt83ow/alice-skill/internal/store/pg/migrations"
+	"go.uber.org/zap"
+)
+
+// advisoryLockID — произвольный ключ advisory-блокировки Postgres, которую
+// Migrate держит на время применения миграций, чтобы несколько одновременно
+// стартующих экземпляров навыка не накатывали схему параллельно.
+const advisoryLockID = 787801
+
+// Migrate применяет ещё не применённые миграции из internal/store/pg/migrations
+// в порядке возрастания версий — всё в рамках одной транзакции, под advisory-
+// блокировкой, с фиксацией применённых версий в таблице schema_migrations.
+// Она заменяет прежний Bootstrap, чьи ошибки CREATE TABLE молча игнорировались
+// и не позволяли развивать схему существующей БД.
+func (s Store) Migrate(ctx context.Context) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// блокировка снимается автоматически по завершении транзакции
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, advisoryLockID); err != nil {
+		return fmt.Errorf("acquiring advisory lock: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version bigint PRIMARY KEY,
+            applied_at timestamp with time zone NOT NULL DEFAULT now()
+        )
+    `); err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	pending, err := pendingMigrations(applied)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		logger.FromContext(ctx).Info("applying migration", zap.Int64("version", m.version), zap.String("name", m.name))
+
+		if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.version); err != nil {
+			return fmt.Errorf("recording migration %s: %w", m.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func appliedVersions(ctx context.Context, tx *sql.Tx) (map[int64]bool, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("loading applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// migration описывает одну версионную миграцию схемы БД.
+type migration struct {
+	version int64
+	name    string
+	sql     string
+}
+
+// pendingMigrations читает все встроенные .sql файлы, сортирует их по
+// номеру версии в имени файла и возвращает те, что ещё не применены.
+func pendingMigrations(applied map[int64]bool) ([]migration, error) {
+	entries, err := migrations.FS.ReadDir(".")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded migrations: %w", err)
+	}
+
+	var all []migration
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+
+		version, err := versionFromName(e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := migrations.FS.ReadFile(e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", e.Name(), err)
+		}
+
+		all = append(all, migration{version: version, name: e.Name(), sql: string(data)})
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].version < all[j].version })
+
+	var pending []migration
+	for _, m := range all {
+		if !applied[m.version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// versionFromName извлекает номер версии из имени файла вида "0001_init.sql".
+func versionFromName(name string) (int64, error) {
+	prefix, _, found := strings.Cut(name, "_")
+	if !found {
+		return 0, fmt.Errorf("migration file %q must be named <version>_<name>.sql", name)
+	}
+
+	var version int64
+	if _, err := fmt.Sscanf(prefix, "%d", &version); err != nil {
+		return 0, fmt.Errorf("migration file %q has invalid version prefix: %w", name, err)
+	}
+	return version, nil
+}
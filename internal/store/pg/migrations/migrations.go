@@ -0,0 +1,10 @@
+// Package migrations встраивает в бинарник SQL-файлы миграций схемы БД.
+package migrations
+
+import "embed"
+
+// FS содержит все файлы миграций, упорядоченные по номеру версии в имени
+// файла (например, "0001_init.sql").
+//
+//go:embed *.sql
+var FS embed.FS
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/wurt83ow/alice-skill/internal/logger"
+	"github.com/wurt83ow/alice-skill/internal/metrics"
 	"github.com/wurt83ow/alice-skill/internal/store"
+	"go.uber.org/zap"
 )
 
 // Store реализует интерфейс store.Store и позволяет взаимодействовать с СУБД PostgreSQL.
@@ -22,51 +26,50 @@ func NewStore(conn *sql.DB) *Store {
 	return &Store{conn: conn}
 }
 
-// Bootstrap подготавливает БД к работе, создавая необходимые таблицы и индексы
-func (s Store) Bootstrap(ctx context.Context) error {
-	// запускаем транзакцию
-	tx, err := s.conn.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
+// Ping проверяет доступность СУБД — используется обработчиком /readyz.
+func (s Store) Ping(ctx context.Context) error {
+	return s.conn.PingContext(ctx)
+}
 
-	// в случае неуспешного коммита все изменения транзакции будут отменены
-	defer tx.Rollback()
-
-	// создаём таблицу пользователей и необходимые индексы
-	tx.ExecContext(ctx, `
-        CREATE TABLE users (
-            id varchar(128) PRIMARY KEY,
-            username varchar(128)
-        )
-    `)
-	tx.ExecContext(ctx, `CREATE UNIQUE INDEX sender_idx ON users (username)`)
-
-	// создаём таблицу сообщений и необходимые индексы
-	tx.ExecContext(ctx, `
-        CREATE TABLE messages (
-            id serial PRIMARY KEY,
-            sender varchar(128),
-            recepient varchar(128),
-            payload text,
-            sent_at timestamp with time zone,
-            read_at timestamp with time zone DEFAULT NULL
-        )
-    `)
-	tx.ExecContext(ctx, `CREATE INDEX recepient_idx ON messages (recepient)`)
-
-	// коммитим транзакцию
-	return tx.Commit()
+// withQueryMetrics выполняет query и фиксирует в metrics.StoreQueryDuration
+// его длительность и исход (метка error) под именем name.
+func withQueryMetrics(name string, query func() error) error {
+	start := time.Now()
+	err := query()
+	metrics.StoreQueryDuration.
+		WithLabelValues(name, strconv.FormatBool(err != nil)).
+		Observe(time.Since(start).Seconds())
+	return err
 }
 
 func (s Store) FindRecepient(ctx context.Context, username string) (userID string, err error) {
-	// запрашиваем внутренний идентификатор пользователя по его имени
-	row := s.conn.QueryRowContext(ctx, `SELECT id FROM users WHERE username = $1`, username)
-	err = row.Scan(&userID)
+	err = withQueryMetrics("find_recepient", func() error {
+		// запрашиваем внутренний идентификатор пользователя по его имени
+		row := s.conn.QueryRowContext(ctx, `SELECT id FROM users WHERE username = $1`, username)
+		scanErr := row.Scan(&userID)
+		if scanErr != nil {
+			if errors.Is(scanErr, sql.ErrNoRows) {
+				// пользователь указал неизвестный или опечатанный логин получателя —
+				// ожидаемый исход, не повод шуметь в логах уровня Error
+				logger.FromContext(ctx).Debug("recepient not found", zap.String("username", username))
+			} else {
+				logger.FromContext(ctx).Error("find recepient query failed", zap.String("username", username), zap.Error(scanErr))
+			}
+		}
+		return scanErr
+	})
 	return
 }
 
-func (s Store) ListMessages(ctx context.Context, userID string) ([]store.Message, error) {
+func (s Store) ListMessages(ctx context.Context, userID string) (messages []store.Message, err error) {
+	err = withQueryMetrics("list_messages", func() error {
+		messages, err = s.listMessages(ctx, userID)
+		return err
+	})
+	return
+}
+
+func (s Store) listMessages(ctx context.Context, userID string) ([]store.Message, error) {
 	// запрашиваем данные обо всех сообщениях пользователя, без самого текста
 	rows, err := s.conn.QueryContext(ctx, `
         SELECT
@@ -103,7 +106,15 @@ func (s Store) ListMessages(ctx context.Context, userID string) ([]store.Message
 	return messages, nil
 }
 
-func (s Store) GetMessage(ctx context.Context, id int64) (*store.Message, error) {
+func (s Store) GetMessage(ctx context.Context, id int64) (msg *store.Message, err error) {
+	err = withQueryMetrics("get_message", func() error {
+		msg, err = s.getMessage(ctx, id)
+		return err
+	})
+	return
+}
+
+func (s Store) getMessage(ctx context.Context, id int64) (*store.Message, error) {
 	// запрашиваем сообщение по внутреннему идентификатору
 	row := s.conn.QueryRowContext(ctx, `
         SELECT
@@ -129,33 +140,55 @@ func (s Store) GetMessage(ctx context.Context, id int64) (*store.Message, error)
 }
 
 func (s Store) SaveMessage(ctx context.Context, userID string, msg store.Message) error {
-	// добавляем новое сообщение в БД
-	_, err := s.conn.ExecContext(ctx, `
+	return withQueryMetrics("save_message", func() error {
+		// idempotency_key хранится как NULL, когда не указан, чтобы не конфликтовать
+		// с другими сообщениями без ключа
+		var key interface{}
+		if msg.Key != "" {
+			key = msg.Key
+		}
+
+		// добавляем новое сообщение в БД
+		_, err := s.conn.ExecContext(ctx, `
         INSERT INTO messages
-        (sender, recepient, payload, sent_at)
+        (sender, recepient, payload, sent_at, idempotency_key)
         VALUES
-        ($1, $2, $3, $4);
-    `, msg.Sender, userID, msg.Payload, time.Now())
+        ($1, $2, $3, $4, $5);
+    `, msg.Sender, userID, msg.Payload, msg.Time, key)
+
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgerrcode.IsIntegrityConstraintViolation(pgErr.Code) {
+				err = store.ErrConflict
+			} else {
+				logger.FromContext(ctx).Error("save message query failed", zap.String("recepient", userID), zap.Error(err))
+			}
+		}
 
-	return err
+		return err
+	})
 }
 
 func (s Store) RegisterUser(ctx context.Context, userID, username string) error {
-	// добавляем новую запись пользователя
-	_, err := s.conn.ExecContext(ctx, `
+	return withQueryMetrics("register_user", func() error {
+		// добавляем новую запись пользователя
+		_, err := s.conn.ExecContext(ctx, `
         INSERT INTO users
         (id, username)
         VALUES
         ($1, $2);
     `, userID, username)
 
-	if err != nil {
-		// проверяем, что ошибка сигнализирует о потенциальном нарушении целостности данных
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgerrcode.IsIntegrityConstraintViolation(pgErr.Code) {
-			err = store.ErrConflict
+		if err != nil {
+			// проверяем, что ошибка сигнализирует о потенциальном нарушении целостности данных
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgerrcode.IsIntegrityConstraintViolation(pgErr.Code) {
+				err = store.ErrConflict
+			} else {
+				logger.FromContext(ctx).Error("register user query failed", zap.String("username", username), zap.Error(err))
+			}
 		}
-	}
 
-	return err
+		return err
+	})
 }
@@ -0,0 +1,43 @@
+// Package store описывает абстрактный интерфейс хранилища данных навыка
+// и независимые от конкретной СУБД типы.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrConflict сигнализирует о том, что сущность с такими данными уже существует.
+var ErrConflict = errors.New("data conflict")
+
+// Store описывает абстрактное хранилище данных навыка. Все зависящие от
+// хранения части приложения должны работать через этот интерфейс, а не
+// напрямую с конкретной реализацией.
+type Store interface {
+	// RegisterUser регистрирует пользователя Алисы с указанным внутренним
+	// идентификатором под указанным именем.
+	RegisterUser(ctx context.Context, userID, username string) error
+	// FindRecepient возвращает внутренний идентификатор адресата по его имени.
+	FindRecepient(ctx context.Context, username string) (userID string, err error)
+	// SaveMessage сохраняет новое сообщение для указанного адресата.
+	SaveMessage(ctx context.Context, userID string, msg Message) error
+	// ListMessages возвращает список входящих сообщений пользователя.
+	ListMessages(ctx context.Context, userID string) ([]Message, error)
+	// GetMessage возвращает сообщение по его внутреннему идентификатору.
+	GetMessage(ctx context.Context, id int64) (*Message, error)
+}
+
+// Message описывает объект сообщения, хранимый в системе.
+type Message struct {
+	ID     int64
+	Sender string
+	Time   time.Time
+	// Payload содержит текст сообщения.
+	Payload string
+	// Key — необязательный ключ идемпотентности. Если указан, повторное
+	// сохранение сообщения с тем же ключом возвращает ErrConflict вместо
+	// создания дубликата; это нужно, чтобы повторная доставка задачи из
+	// очереди не приводила к дублированию сообщений.
+	Key string
+}
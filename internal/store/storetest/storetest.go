@@ -0,0 +1,108 @@
+// Package storetest содержит общий набор конформанс-тестов, которому должна
+// удовлетворять любая реализация store.Store — чтобы RegisterUser, SaveMessage
+// и порядок сообщений вели себя одинаково независимо от бэкенда.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/alice-skill/internal/store"
+)
+
+// Run прогоняет конформанс-тесты по хранилищу, возвращаемому newStore.
+// newStore вызывается для каждого подтеста и должно возвращать пустое,
+// независимое от других вызовов хранилище.
+func Run(t *testing.T, newStore func(t *testing.T) store.Store) {
+	t.Run("RegisterUser conflict", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+
+		require.NoError(t, s.RegisterUser(ctx, "user-1", "alice"))
+
+		err := s.RegisterUser(ctx, "user-2", "alice")
+		assert.ErrorIs(t, err, store.ErrConflict)
+	})
+
+	t.Run("FindRecepient", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+
+		require.NoError(t, s.RegisterUser(ctx, "user-1", "alice"))
+
+		userID, err := s.FindRecepient(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", userID)
+
+		_, err = s.FindRecepient(ctx, "unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("messages are returned in send order", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+
+		require.NoError(t, s.RegisterUser(ctx, "sender", "bob"))
+		require.NoError(t, s.RegisterUser(ctx, "recepient", "alice"))
+
+		const n = 3
+		for i := 0; i < n; i++ {
+			require.NoError(t, s.SaveMessage(ctx, "recepient", store.Message{
+				Sender:  "sender",
+				Time:    time.Now(),
+				Payload: fmt.Sprintf("message %d", i),
+			}))
+		}
+
+		messages, err := s.ListMessages(ctx, "recepient")
+		require.NoError(t, err)
+		require.Len(t, messages, n)
+
+		for i := 1; i < len(messages); i++ {
+			assert.Less(t, messages[i-1].ID, messages[i].ID)
+		}
+		for _, m := range messages {
+			assert.Equal(t, "bob", m.Sender)
+		}
+	})
+
+	t.Run("GetMessage returns saved payload", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+
+		require.NoError(t, s.RegisterUser(ctx, "sender", "bob"))
+		require.NoError(t, s.RegisterUser(ctx, "recepient", "alice"))
+		require.NoError(t, s.SaveMessage(ctx, "recepient", store.Message{
+			Sender:  "sender",
+			Time:    time.Now(),
+			Payload: "hello",
+		}))
+
+		messages, err := s.ListMessages(ctx, "recepient")
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+
+		msg, err := s.GetMessage(ctx, messages[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", msg.Payload)
+		assert.Equal(t, "bob", msg.Sender)
+	})
+
+	t.Run("SaveMessage with repeated idempotency key conflicts", func(t *testing.T) {
+		s := newStore(t)
+		ctx := context.Background()
+
+		require.NoError(t, s.RegisterUser(ctx, "sender", "bob"))
+		require.NoError(t, s.RegisterUser(ctx, "recepient", "alice"))
+
+		msg := store.Message{Sender: "sender", Time: time.Now(), Payload: "hi", Key: "idem-1"}
+		require.NoError(t, s.SaveMessage(ctx, "recepient", msg))
+
+		err := s.SaveMessage(ctx, "recepient", msg)
+		assert.ErrorIs(t, err, store.ErrConflict)
+	})
+}
@@ -0,0 +1,46 @@
+// Package worker содержит обработчики задач очереди, общие для воркера
+// cmd/worker и cmd/skill (который регистрирует их на обработчике в
+// процессе, когда для queue.driver выбран "memory").
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/wurt83ow/alice-skill/internal/logger"
+	"github.com/wurt83ow/alice-skill/internal/queue"
+	"github.com/wurt83ow/alice-skill/internal/store"
+	"go.uber.org/zap"
+)
+
+// HandleSendMessage возвращает обработчик задачи queue.TypeSendMessage,
+// сохраняющий доставляемое сообщение в хранилище. Ключ идемпотентности в
+// payload гарантирует, что повторная доставка задачи после сбоя не создаст
+// дубликат сообщения.
+func HandleSendMessage(s store.Store) queue.Handler {
+	return func(ctx context.Context, task queue.Task) error {
+		var payload queue.SendMessagePayload
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			// повторная обработка не исправит некорректный payload —
+			// сообщаем очереди не ретраить эту задачу, просто логируем потерю
+			logger.Log.Error("cannot unmarshal send message task", zap.Error(err))
+			return nil
+		}
+
+		ctx = logger.WithRequestID(ctx, payload.RequestID)
+
+		err := s.SaveMessage(ctx, payload.RecepientID, store.Message{
+			Sender:  payload.Sender,
+			Time:    payload.SentAt,
+			Payload: payload.Text,
+			Key:     payload.IdempotencyKey,
+		})
+		if err != nil && !errors.Is(err, store.ErrConflict) {
+			logger.FromContext(ctx).Error("cannot save message", zap.Error(err))
+			return err
+		}
+
+		return nil
+	}
+}